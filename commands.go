@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/wwqdrh/gddon/resolve"
+	"github.com/wwqdrh/gddon/source"
 )
 
 // GddonObject represents the main configuration structure
@@ -15,12 +17,14 @@ type GddonObject struct {
 	Packages []GddonPackage `json:"packages"`
 }
 
-// GddonPackage represents a package in the configuration
+// GddonPackage represents a package in the configuration. Source can be a
+// git remote, an HTTP(S) archive, or an s3://.../gs://... object URL; see
+// package source for how it is parsed and fetched.
 type GddonPackage struct {
-	Name    string `json:"name"`
-	GitRepo string `json:"git_repo"`
-	Commit  string `json:"commit"`
-	Links   []Link `json:"links"`
+	Name    string         `json:"name"`
+	Source  string         `json:"source"`
+	Version source.Version `json:"version"`
+	Links   []Link         `json:"links"`
 }
 
 // Link represents a file link between source and target
@@ -44,19 +48,6 @@ func logCheck(message string) {
 	fmt.Printf("\033[32mâœ“ %s\033[0m\n", message)
 }
 
-// runShellCommand executes a shell command
-func runShellCommand(command string, workingDir string, verbose bool) error {
-	cmd := exec.Command("sh", "-c", command)
-	cmd.Dir = workingDir
-
-	if verbose {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	}
-
-	return cmd.Run()
-}
-
 // assertResult checks if an error exists and logs it
 func assertResult(err error, message string) {
 	if err != nil {
@@ -151,9 +142,74 @@ func CheckInitialization(root string) bool {
 		logError(".gddon.d/ folder does not exist!")
 		ret = false
 	}
+
+	if ret {
+		warnIfLockDrifted(root)
+	}
 	return ret
 }
 
+// warnIfLockDrifted compares .gddon.lock against .gddon and logs a
+// warning (without failing) when a package listed in .gddon has no entry
+// in .gddon.lock, which means `install` would be reading a stale
+// resolution. Only the first such package is reported; locked packages no
+// longer in .gddon, and changed sources or versions, are not detected.
+func warnIfLockDrifted(root string) {
+	lock, ok := readLockFile(lockFilePath(root))
+	if !ok {
+		return
+	}
+
+	gddonObject := readGddonFile(filepath.Join(root, ".gddon"))
+	manifestNames := map[string]bool{}
+	for _, pkg := range gddonObject.Packages {
+		manifestNames[pkg.Name] = true
+	}
+
+	lockedNames := map[string]bool{}
+	for _, pkg := range lock.Packages {
+		lockedNames[pkg.Name] = true
+	}
+
+	for name := range manifestNames {
+		if !lockedNames[name] {
+			logError(fmt.Sprintf(".gddon.lock is out of date: %q is in .gddon but not locked, run `gddon add`/`update` to regenerate it", name))
+			return
+		}
+	}
+}
+
+// lockFilePath returns the path to the project's lockfile.
+func lockFilePath(root string) string {
+	return filepath.Join(root, ".gddon.lock")
+}
+
+// readLockFile reads the lockfile at filePath. ok is false when the file
+// does not exist yet, which is not an error: projects without transitive
+// dependencies may never have one.
+func readLockFile(filePath string) (resolve.Lockfile, bool) {
+	content, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return resolve.Lockfile{}, false
+	}
+	assertResult(err, "Couldn't read .gddon.lock file!")
+
+	var lock resolve.Lockfile
+	err = json.Unmarshal(content, &lock)
+	assertResult(err, "Couldn't parse .gddon.lock file!")
+
+	return lock, true
+}
+
+// writeLockFile writes the fully-resolved dependency graph to filePath.
+func writeLockFile(filePath string, lock *resolve.Lockfile) {
+	jsonData, err := json.MarshalIndent(lock, "", "  ")
+	assertResult(err, "Couldn't marshal .gddon.lock file!")
+
+	err = os.WriteFile(filePath, jsonData, 0644)
+	assertResult(err, "Couldn't write .gddon.lock file!")
+}
+
 // Helper functions for creating configuration files
 func createGitIgnoreFile() string {
 	return `# Godot-specific ignores
@@ -176,83 +232,6 @@ func createGddonFile() string {
 }`
 }
 
-// InstallRepositories installs all packages defined in the ,gddon file
-func InstallRepositories(root string, verbose bool) {
-	gddonFilePath := filepath.Join(root, ".gddon")
-	gddonObject := readGddonFile(gddonFilePath)
-
-	for i := range gddonObject.Packages {
-		pkg := &gddonObject.Packages[i]
-		logInfo(fmt.Sprintf("Installing %s...", pkg.Name))
-		cloneOrFetchPackage(root, pkg, verbose)
-		installGddonPackage(root, pkg.Commit, pkg, false, true, verbose)
-	}
-
-	writeGddonFile(gddonFilePath, &gddonObject)
-}
-
-// AddRepository adds a new repository to the project
-func AddRepository(root string, gitRepo string, verbose bool) {
-	gddonFilePath := filepath.Join(root, ".gddon")
-	gddonObject := readGddonFile(gddonFilePath)
-
-	if findPackageByRepository(gddonObject.Packages, gitRepo) != -1 {
-		logError("Repository already exists!")
-		os.Exit(1)
-	}
-
-	defaultName := getRepoName(gitRepo)
-	name := promptText("Name of the addon:", defaultName)
-
-	if findPackageByName(gddonObject.Packages, name) != -1 {
-		logError("Addon name exists!")
-		os.Exit(1)
-	}
-
-	commit := promptText("Commit hash of the repository:", "latest")
-
-	newPackage := GddonPackage{
-		Name:    name,
-		GitRepo: gitRepo,
-		Commit:  commit,
-		Links:   []Link{},
-	}
-
-	gddonObject.Packages = append(gddonObject.Packages, newPackage)
-	targetPackage := &gddonObject.Packages[len(gddonObject.Packages)-1]
-
-	cloneOrFetchPackage(root, targetPackage, verbose)
-	installGddonPackage(root, commit, targetPackage, false, true, verbose)
-
-	writeGddonFile(gddonFilePath, &gddonObject)
-}
-
-// UpdateRepository updates a specific repository
-func UpdateRepository(root string, verbose bool) {
-	gddonFilePath := filepath.Join(root, ".gddon")
-	gddonObject := readGddonFile(gddonFilePath)
-
-	if len(gddonObject.Packages) == 0 {
-		logError("No addons to update!")
-		os.Exit(1)
-	}
-
-	options := make([]string, len(gddonObject.Packages))
-	for i, pkg := range gddonObject.Packages {
-		options[i] = pkg.Name
-	}
-
-	ans := promptSelect("Which addon you want to update?", options)
-	packageIndex := findPackageByName(gddonObject.Packages, ans)
-	targetPackage := &gddonObject.Packages[packageIndex]
-
-	logInfo(fmt.Sprintf("Updating %s...", targetPackage.Name))
-	cloneOrFetchPackage(root, targetPackage, verbose)
-	installGddonPackage(root, "", targetPackage, true, true, verbose)
-
-	writeGddonFile(gddonFilePath, &gddonObject)
-}
-
 // Helper functions for package management
 func readGddonFile(filePath string) GddonObject {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
@@ -287,177 +266,22 @@ func findPackageByName(packages []GddonPackage, name string) int {
 	return -1
 }
 
-func findPackageByRepository(packages []GddonPackage, repo string) int {
+func findPackageBySource(packages []GddonPackage, src string) int {
 	for i, pkg := range packages {
-		if pkg.GitRepo == repo {
+		if pkg.Source == src {
 			return i
 		}
 	}
 	return -1
 }
 
-func getRepoName(gitRepo string) string {
-	parts := strings.Split(gitRepo, "/")
-	return strings.TrimSuffix(parts[len(parts)-1], ".git")
-}
-
-// cloneOrFetchPackage clones or fetches updates for a package
-func cloneOrFetchPackage(root string, package_ *GddonPackage, verbose bool) {
-	packagePath := filepath.Join(root, ".gddon.d", package_.Name)
-
-	if _, err := os.Stat(packagePath); os.IsNotExist(err) {
-		cmd := fmt.Sprintf("cd .gddon.d/ && git clone %s %s --progress", package_.GitRepo, package_.Name)
-		err := runShellCommand(cmd, root, verbose)
-		assertResult(err, "Couldn't clone repository!")
-		logCheck("Created package folder on .gddon.d")
-	} else {
-		if package_.GitRepo == "" {
-			cmd := fmt.Sprintf("cd .gddon.d/%s && git remote get-url origin", package_.Name)
-			out, err := exec.Command("sh", "-c", cmd).Output()
-			if err != nil {
-				logError("GDDON Package has no origin yet!")
-				os.Exit(1)
-			}
-			package_.GitRepo = strings.TrimSpace(string(out))
-		}
-
-		cmd := fmt.Sprintf("cd .gddon.d/%s && git fetch origin && git pull", package_.Name)
-		err := runShellCommand(cmd, root, verbose)
-		assertResult(err, "Couldn't fetch package repository updates!")
-		logInfo("Glam package folder already exists, fetched and pulled latest changes")
-	}
-}
-
-// installGddonPackage installs a package to the project
-func installGddonPackage(root string, commit string, package_ *GddonPackage, updatePackage bool, copyFiles bool, verbose bool) {
-	if updatePackage {
-		package_.Commit = "latest"
-	}
-
-	if commit != "latest" {
-		package_.Commit = commit
-	}
-
-	// Get all folders in addon
-	folders := listDir(fmt.Sprintf(".gddon.d/%s/addons", package_.Name))
-	if len(package_.Links) == 0 {
-		if len(folders) == 1 {
-			package_.Links = append(package_.Links, Link{
-				TargetFolder: fmt.Sprintf("addons/%s", folders[0]),
-				SourceFolder: fmt.Sprintf("addons/%s", folders[0]),
-			})
-		} else {
-			// TODO: Implement multi-select prompt
-			logError("Multiple addons not yet supported")
-			os.Exit(1)
-		}
-	}
-
-	if package_.Commit == "latest" {
-		cmd := fmt.Sprintf("cd .gddon.d/%s && git rev-parse HEAD", package_.Name)
-		out, err := exec.Command("sh", "-c", cmd).Output()
-		assertResult(err, "Couldn't get latest commit!")
-		package_.Commit = strings.TrimSpace(string(out))
-	} else {
-		logInfo("Git checkout to package commit")
-		cmd := fmt.Sprintf("cd .gddon.d/%s && git reset --hard %s", package_.Name, package_.Commit)
-		err := runShellCommand(cmd, root, verbose)
-		assertResult(err, "Couldn't checkout repository!")
-	}
-
-	if copyFiles {
-		for _, link := range package_.Links {
-			// Create target directory if it doesn't exist
-			targetPath := filepath.Join(root, link.TargetFolder)
-			err := os.MkdirAll(targetPath, 0755)
-			assertResult(err, "Couldn't create addons folder!")
-
-			// Copy files
-			sourcePath := filepath.Join(root, ".gddon.d", package_.Name, link.SourceFolder)
-			// cmd := fmt.Sprintf("cp -rf %s/* %s", sourcePath, targetPath)
-			// err = runShellCommand(cmd, root, verbose)
-			err = copyDir(targetPath, sourcePath)
-			assertResult(err, "Couldn't copy files to addons!")
-		}
-	}
-}
-
-// promptText prompts the user for text input
-func promptText(prompt string, defaultValue string) string {
-	fmt.Printf("%s [%s]: ", prompt, defaultValue)
-	var input string
-	fmt.Scanln(&input)
-	if input == "" {
-		return defaultValue
-	}
-	return input
-}
-
-// promptSelect prompts the user to select from a list of options
-func promptSelect(prompt string, options []string) string {
-	fmt.Println(prompt)
-	for i, option := range options {
-		fmt.Printf("%d. %s\n", i+1, option)
-	}
-
-	var choice int
-	for {
-		fmt.Print("Enter your choice (1-", len(options), "): ")
-		_, err := fmt.Scan(&choice)
-		if err == nil && choice >= 1 && choice <= len(options) {
-			return options[choice-1]
-		}
-		fmt.Println("Invalid choice. Please try again.")
-	}
-}
-
-// CreateAddon creates a new addon package
-func CreateAddon(root string, verbose bool) {
-	gddonFilePath := filepath.Join(root, ".gddon")
-	gddonObject := readGddonFile(gddonFilePath)
-
-	folders := listAddons(root, verbose)
-	if len(folders) == 0 {
-		logError("No addons found in the project!")
-		os.Exit(1)
-	}
-
-	addonName := promptSelect("Which addon you'll create a repository?", folders)
-
-	if findPackageByLink(gddonObject.Packages, addonName) != -1 {
-		logError("There is a repository linked to that addon already!")
-		os.Exit(1)
-	}
-
-	repoName := promptText("Name of the repository:", addonName)
-
-	// Create repository structure
-	repoPath := filepath.Join(root, ".gddon.d", repoName, "addons", addonName)
-	err := os.MkdirAll(repoPath, 0755)
-	assertResult(err, "Repository folder failed to be created!")
-
-	// Initialize git repository
-	cmd := fmt.Sprintf("cd .gddon.d/%s && git init", repoName)
-	err = runShellCommand(cmd, root, verbose)
-	assertResult(err, "Repository failed to be initialized!")
-
-	// Add package to configuration
-	gddonObject.Packages = append(gddonObject.Packages, GddonPackage{
-		Name:    repoName,
-		GitRepo: "",
-		Commit:  "",
-		Links: []Link{
-			{
-				TargetFolder: fmt.Sprintf("addons/%s", addonName),
-				SourceFolder: fmt.Sprintf("addons/%s", addonName),
-			},
-		},
-	})
-
-	writeGddonFile(gddonFilePath, &gddonObject)
-
-	targetPackage := &gddonObject.Packages[len(gddonObject.Packages)-1]
-	applyPackageFiles(root, targetPackage, verbose)
+func getRepoName(src string) string {
+	parts := strings.Split(src, "/")
+	name := parts[len(parts)-1]
+	name = strings.TrimSuffix(name, ".git")
+	name = strings.TrimSuffix(name, ".zip")
+	name = strings.TrimSuffix(name, ".tar.gz")
+	return name
 }
 
 // ApplyChanges applies changes from the project to a selected package
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// acquireInstallLock takes an exclusive PID-file lock at lockPath so two
+// `gddon install` processes can't run concurrently and clobber the same
+// .gddon.d checkouts. Call the returned release func (e.g. via defer)
+// once installation finishes.
+func acquireInstallLock(lockPath string) (release func(), err error) {
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			holder, _ := os.ReadFile(lockPath)
+			return nil, fmt.Errorf("another gddon install is already running (pid %s); remove %s if that's stale", strings.TrimSpace(string(holder)), lockPath)
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "%d", os.Getpid())
+
+	return func() { os.Remove(lockPath) }, nil
+}
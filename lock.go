@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/wwqdrh/gddon/resolve"
+	"github.com/wwqdrh/gddon/source"
+)
+
+// resolveLock walks gddonObject's packages and their transitive
+// gddon.json dependencies, fetching each distinct addon exactly once,
+// and returns the flattened, exactly-pinned result.
+func (m *Manager) resolveLock(root string, gddonObject *GddonObject) *resolve.Lockfile {
+	roots := make([]resolve.Requirement, len(gddonObject.Packages))
+	pinned := map[string]source.Version{}
+	for i, pkg := range gddonObject.Packages {
+		roots[i] = resolve.Requirement{Name: pkg.Name, Source: pkg.Source}
+		if pkg.Version != (source.Version{}) {
+			pinned[pkg.Name] = pkg.Version
+		}
+	}
+
+	fetch := func(req resolve.Requirement) (string, source.Version, error) {
+		packagePath := filepath.Join(root, ".gddon.d", req.Name)
+
+		if req.Source == "" {
+			// A `gddon create`d local addon has no remote to fetch from;
+			// its checkout already lives at packagePath, so treat it as
+			// locally pinned instead of calling a Fetcher that would
+			// require an "origin" CreateAddon never configures.
+			return packagePath, pinned[req.Name], nil
+		}
+
+		scheme, location, err := source.Parse(req.Source)
+		if err != nil {
+			return "", source.Version{}, err
+		}
+
+		fetcher, err := m.Fetchers.For(scheme)
+		if err != nil {
+			return "", source.Version{}, err
+		}
+
+		// Honor a version already pinned in .gddon (e.g. by `gddon add`)
+		// instead of silently resolving to latest on a first-time lock.
+		var pin *source.Version
+		if v, ok := pinned[req.Name]; ok {
+			pin = &v
+		}
+
+		resolved, err := fetcher.Fetch(location, packagePath, pin)
+		if err != nil {
+			return "", source.Version{}, err
+		}
+
+		return packagePath, resolved, nil
+	}
+
+	lock, err := resolve.Resolve(roots, fetch)
+	assertResult(err, "Couldn't resolve addon dependencies!")
+	return lock
+}
+
+// installFromLockParallel fetches every package recorded in lock, pinned
+// to the exact Version it was resolved to, without re-running the
+// resolver. Source-less (`gddon create`d) packages are skipped, since
+// their checkout already lives in .gddon.d/. Fetches run concurrently,
+// bounded by jobs (<= 0 defaults to runtime.NumCPU()), since each
+// package's checkout is independent once the lock has fixed every version.
+func (m *Manager) installFromLockParallel(root string, lock resolve.Lockfile, jobs int) {
+	jobFns := make([]func() error, len(lock.Packages))
+	for i, locked := range lock.Packages {
+		locked := locked
+		jobFns[i] = func() error {
+			if locked.Source == "" {
+				return nil
+			}
+
+			logInfo(fmt.Sprintf("Fetching %s...", locked.Name))
+
+			packagePath := filepath.Join(root, ".gddon.d", locked.Name)
+
+			scheme, location, err := source.Parse(locked.Source)
+			if err != nil {
+				return fmt.Errorf("%s: %w", locked.Name, err)
+			}
+
+			fetcher, err := m.Fetchers.For(scheme)
+			if err != nil {
+				return fmt.Errorf("%s: %w", locked.Name, err)
+			}
+
+			pinned := locked.Version
+			if _, err := fetcher.Fetch(location, packagePath, &pinned); err != nil {
+				return fmt.Errorf("%s: %w", locked.Name, err)
+			}
+
+			logCheck(fmt.Sprintf("Fetched %s", locked.Name))
+			return nil
+		}
+	}
+
+	errs := NewPool(jobs).Run(jobFns)
+	for _, err := range errs {
+		assertResult(err, "Couldn't fetch locked package!")
+	}
+}
+
+// versionOf looks up the resolved Version for name inside lock.
+func versionOf(lock resolve.Lockfile, name string) (source.Version, bool) {
+	for _, locked := range lock.Packages {
+		if locked.Name == name {
+			return locked.Version, true
+		}
+	}
+	return source.Version{}, false
+}
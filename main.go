@@ -5,9 +5,17 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/wwqdrh/gddon/vcs"
 )
 
 var verbose bool
+var vcsBackend string
+
+// manager builds the Manager for the backend kind selected via --backend.
+func manager() *Manager {
+	return DefaultManager(vcs.Kind(vcsBackend))
+}
 
 var rootCmd = &cobra.Command{
 	Use:   "gddon",
@@ -31,7 +39,7 @@ var addCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		root := SearchProjectRoot()
 		if CheckInitialization(root) {
-			AddRepository(root, args[0], verbose)
+			manager().AddRepository(root, args[0], verbose)
 		}
 	},
 }
@@ -42,7 +50,7 @@ var createCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		root := SearchProjectRoot()
 		if CheckInitialization(root) {
-			CreateAddon(root, verbose)
+			manager().CreateAddon(root, verbose)
 		}
 	},
 }
@@ -53,18 +61,20 @@ var updateCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		root := SearchProjectRoot()
 		if CheckInitialization(root) {
-			UpdateRepository(root, verbose)
+			manager().UpdateRepository(root, verbose)
 		}
 	},
 }
 
+var installJobs int
+
 var installCmd = &cobra.Command{
 	Use:   "install",
 	Short: "Install all addons on gddons file",
 	Run: func(cmd *cobra.Command, args []string) {
 		root := SearchProjectRoot()
 		if CheckInitialization(root) {
-			InstallRepositories(root, verbose)
+			manager().InstallRepositories(root, verbose, installJobs)
 		}
 	},
 }
@@ -80,8 +90,38 @@ var applyCmd = &cobra.Command{
 	},
 }
 
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Interactively manage installed addons",
+	Run: func(cmd *cobra.Command, args []string) {
+		root := SearchProjectRoot()
+		if CheckInitialization(root) {
+			manager().RunTUI(root, verbose)
+		}
+	},
+}
+
+var outdatedJSON bool
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Report how far installed addons are behind upstream",
+	Run: func(cmd *cobra.Command, args []string) {
+		root := SearchProjectRoot()
+		if CheckInitialization(root) {
+			if manager().CheckOutdated(root, verbose, outdatedJSON) {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose (output subshell commands)")
+	rootCmd.PersistentFlags().StringVar(&vcsBackend, "backend", string(vcs.KindGoGit),
+		fmt.Sprintf("VCS backend to use (%q or %q). %q also enables shared mirror installs.", vcs.KindGoGit, vcs.KindExecGit, vcs.KindExecGit))
+	installCmd.Flags().IntVar(&installJobs, "jobs", 0, "Number of packages to install concurrently (default: number of CPUs)")
+	outdatedCmd.Flags().BoolVar(&outdatedJSON, "json", false, "Output the report as JSON for CI consumption")
 
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(addCmd)
@@ -89,6 +129,8 @@ func init() {
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(installCmd)
 	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(outdatedCmd)
+	rootCmd.AddCommand(tuiCmd)
 }
 
 func main() {
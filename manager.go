@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/wwqdrh/gddon/source"
+	"github.com/wwqdrh/gddon/vcs"
+)
+
+// Manager owns every operation that touches a package's checkout under
+// .gddon.d/. Repo access is routed through a vcs.Backend, and package
+// fetching in general through a source.Fetchers set keyed by scheme, so
+// tests can swap in fakes instead of hitting the network.
+type Manager struct {
+	Backend  vcs.Backend
+	Fetchers source.Fetchers
+}
+
+// NewManager builds a Manager around the given backend and fetchers.
+func NewManager(backend vcs.Backend, fetchers source.Fetchers) *Manager {
+	return &Manager{Backend: backend, Fetchers: fetchers}
+}
+
+// DefaultManager builds a Manager using the given vcs backend kind (an
+// empty kind defaults to go-git, see vcs.New) and the full set of built-in
+// fetchers (git, HTTP archive, S3, GCS). Mirror/worktree installs only
+// kick in for backends that implement vcs.MirrorBackend, i.e. exec-git.
+func DefaultManager(kind vcs.Kind) *Manager {
+	backend, err := vcs.New(kind)
+	assertResult(err, "Couldn't set up vcs backend!")
+
+	fetchers := source.Fetchers{
+		source.SchemeGit:  &source.GitFetcher{Backend: backend, UseMirrors: true},
+		source.SchemeHTTP: &source.HTTPFetcher{},
+		source.SchemeS3:   &source.S3Fetcher{},
+		source.SchemeGCS:  &source.GCSFetcher{},
+	}
+
+	return &Manager{Backend: backend, Fetchers: fetchers}
+}
+
+// InstallRepositories installs all packages defined in the ,gddon file. If
+// a .gddon.lock is present it is read verbatim for reproducibility;
+// otherwise dependencies are resolved fresh and the lock is created.
+// When installing from an existing lock, package fetches run concurrently,
+// bounded by jobs (<= 0 defaults to runtime.NumCPU()); a fresh resolve
+// fetches packages one at a time. Copying the linked folders into the
+// project runs on a pool of the same size. A file lock at .gddon.d/.lock
+// keeps two `gddon install` processes from clobbering the same checkouts.
+func (m *Manager) InstallRepositories(root string, verbose bool, jobs int) {
+	release, err := acquireInstallLock(filepath.Join(root, ".gddon.d", ".lock"))
+	if err != nil {
+		logError(err.Error())
+		os.Exit(1)
+	}
+	defer release()
+
+	gddonFilePath := filepath.Join(root, ".gddon")
+	gddonObject := readGddonFile(gddonFilePath)
+
+	lockPath := lockFilePath(root)
+	lock, ok := readLockFile(lockPath)
+	if !ok {
+		logInfo("No .gddon.lock found, resolving dependencies...")
+		resolved := m.resolveLock(root, &gddonObject)
+		lock = *resolved
+		writeLockFile(lockPath, &lock)
+	} else {
+		logInfo("Installing from .gddon.lock")
+		m.installFromLockParallel(root, lock, jobs)
+	}
+
+	// Resolving which addons/* folders link into the project prompts the
+	// user when a package has more than one and nothing was chosen yet.
+	// That has to happen here, one package at a time, before the pool
+	// below runs installGddonPackage concurrently -- two packages
+	// prompting via survey at the same time race on terminal state.
+	for i := range gddonObject.Packages {
+		pkg := &gddonObject.Packages[i]
+		if version, ok := versionOf(lock, pkg.Name); ok {
+			pkg.Version = version
+		}
+		m.resolveLinks(pkg)
+	}
+
+	pool := NewPool(jobs)
+	jobFns := make([]func() error, len(gddonObject.Packages))
+	for i := range gddonObject.Packages {
+		pkg := &gddonObject.Packages[i]
+		jobFns[i] = func() error {
+			logInfo(fmt.Sprintf("Installing %s...", pkg.Name))
+			m.installGddonPackage(root, pkg)
+			return nil
+		}
+	}
+	pool.Run(jobFns)
+
+	writeGddonFile(gddonFilePath, &gddonObject)
+}
+
+// AddRepository adds a new repository to the project.
+func (m *Manager) AddRepository(root string, src string, verbose bool) {
+	gddonFilePath := filepath.Join(root, ".gddon")
+	gddonObject := readGddonFile(gddonFilePath)
+
+	if findPackageBySource(gddonObject.Packages, src) != -1 {
+		logError("Repository already exists!")
+		os.Exit(1)
+	}
+
+	defaultName := getRepoName(src)
+	name := promptText("Name of the addon:", defaultName)
+
+	if findPackageByName(gddonObject.Packages, name) != -1 {
+		logError("Addon name exists!")
+		os.Exit(1)
+	}
+
+	commit := promptText("Commit hash of the repository (blank for latest):", "")
+
+	newPackage := GddonPackage{
+		Name:    name,
+		Source:  src,
+		Version: source.Version{Commit: commit},
+		Links:   []Link{},
+	}
+
+	gddonObject.Packages = append(gddonObject.Packages, newPackage)
+	targetPackage := &gddonObject.Packages[len(gddonObject.Packages)-1]
+
+	var pinned *source.Version
+	if commit != "" {
+		pinned = &targetPackage.Version
+	}
+	m.fetchPackage(root, targetPackage, pinned)
+	m.installGddonPackage(root, targetPackage)
+
+	writeGddonFile(gddonFilePath, &gddonObject)
+
+	lock := m.resolveLock(root, &gddonObject)
+	writeLockFile(lockFilePath(root), lock)
+}
+
+// UpdateRepository updates a specific repository.
+func (m *Manager) UpdateRepository(root string, verbose bool) {
+	gddonFilePath := filepath.Join(root, ".gddon")
+	gddonObject := readGddonFile(gddonFilePath)
+
+	if len(gddonObject.Packages) == 0 {
+		logError("No addons to update!")
+		os.Exit(1)
+	}
+
+	options := make([]string, len(gddonObject.Packages))
+	for i, pkg := range gddonObject.Packages {
+		options[i] = pkg.Name
+	}
+
+	ans := promptSelect("Which addon you want to update?", options)
+	packageIndex := findPackageByName(gddonObject.Packages, ans)
+	targetPackage := &gddonObject.Packages[packageIndex]
+
+	logInfo(fmt.Sprintf("Updating %s...", targetPackage.Name))
+	m.fetchPackage(root, targetPackage, nil)
+	m.installGddonPackage(root, targetPackage)
+
+	writeGddonFile(gddonFilePath, &gddonObject)
+
+	lock := m.resolveLock(root, &gddonObject)
+	writeLockFile(lockFilePath(root), lock)
+}
+
+// CreateAddon creates a new addon package.
+func (m *Manager) CreateAddon(root string, verbose bool) {
+	gddonFilePath := filepath.Join(root, ".gddon")
+	gddonObject := readGddonFile(gddonFilePath)
+
+	folders := listAddons(root, verbose)
+	if len(folders) == 0 {
+		logError("No addons found in the project!")
+		os.Exit(1)
+	}
+
+	addonName := promptSelect("Which addon you'll create a repository?", folders)
+
+	if findPackageByLink(gddonObject.Packages, addonName) != -1 {
+		logError("There is a repository linked to that addon already!")
+		os.Exit(1)
+	}
+
+	repoName := promptText("Name of the repository:", addonName)
+
+	// Create repository structure
+	repoPath := filepath.Join(root, ".gddon.d", repoName, "addons", addonName)
+	err := os.MkdirAll(repoPath, 0755)
+	assertResult(err, "Repository folder failed to be created!")
+
+	// Initialize git repository
+	err = m.Backend.Init(filepath.Join(root, ".gddon.d", repoName))
+	assertResult(err, "Repository failed to be initialized!")
+
+	// Add package to configuration
+	gddonObject.Packages = append(gddonObject.Packages, GddonPackage{
+		Name:   repoName,
+		Source: "",
+		Links: []Link{
+			{
+				TargetFolder: fmt.Sprintf("addons/%s", addonName),
+				SourceFolder: fmt.Sprintf("addons/%s", addonName),
+			},
+		},
+	})
+
+	writeGddonFile(gddonFilePath, &gddonObject)
+
+	targetPackage := &gddonObject.Packages[len(gddonObject.Packages)-1]
+	applyPackageFiles(root, targetPackage, verbose)
+}
+
+// fetchPackage fetches or updates package_ in .gddon.d/<name> using the
+// Fetcher registered for its source's scheme, and records the resolved
+// Version back onto package_. pinned, when non-nil, asks the fetcher to
+// reproduce that exact version instead of the latest one.
+func (m *Manager) fetchPackage(root string, package_ *GddonPackage, pinned *source.Version) {
+	packagePath := filepath.Join(root, ".gddon.d", package_.Name)
+
+	scheme, location, err := source.Parse(package_.Source)
+	assertResult(err, "Couldn't parse package source!")
+
+	if scheme == source.SchemeGit && location == "" {
+		if _, statErr := os.Stat(packagePath); statErr == nil {
+			remoteURL, remoteErr := m.Backend.RemoteURL(packagePath)
+			if remoteErr != nil {
+				logError("GDDON Package has no origin yet!")
+				os.Exit(1)
+			}
+			location = remoteURL
+			package_.Source = remoteURL
+		}
+	}
+
+	fetcher, err := m.Fetchers.For(scheme)
+	assertResult(err, fmt.Sprintf("No fetcher available for %q", package_.Source))
+
+	resolved, err := fetcher.Fetch(location, packagePath, pinned)
+	assertResult(err, "Couldn't fetch package!")
+	package_.Version = resolved
+
+	logCheck(fmt.Sprintf("Fetched %s to %s", package_.Name, package_.Version))
+}
+
+// resolveLinks wires up the package's addon folders into Links the first
+// time it is installed. When there's more than one folder under addons/
+// and nothing was chosen yet, it prompts the user to pick which ones to
+// install -- callers that install several packages concurrently must call
+// this for every package *before* fanning out, since concurrent survey
+// prompts race on terminal state.
+func (m *Manager) resolveLinks(package_ *GddonPackage) {
+	if len(package_.Links) != 0 {
+		return
+	}
+
+	folders := listDir(filepath.Join(".gddon.d", package_.Name, "addons"))
+	switch len(folders) {
+	case 0:
+		// Nothing under addons/, e.g. a freshly `gddon create`d repo.
+	case 1:
+		package_.Links = append(package_.Links, Link{
+			TargetFolder: fmt.Sprintf("addons/%s", folders[0]),
+			SourceFolder: fmt.Sprintf("addons/%s", folders[0]),
+		})
+	default:
+		chosen := promptMultiSelect(fmt.Sprintf("%s has multiple addons, which do you want to install?", package_.Name), folders)
+		for _, folder := range chosen {
+			package_.Links = append(package_.Links, Link{
+				TargetFolder: fmt.Sprintf("addons/%s", folder),
+				SourceFolder: fmt.Sprintf("addons/%s", folder),
+			})
+		}
+	}
+}
+
+// installGddonPackage resolves the package's Links if not already set, then
+// copies the linked folders into the project.
+func (m *Manager) installGddonPackage(root string, package_ *GddonPackage) {
+	m.resolveLinks(package_)
+
+	for _, link := range package_.Links {
+		// Create target directory if it doesn't exist
+		targetPath := filepath.Join(root, link.TargetFolder)
+		err := os.MkdirAll(targetPath, 0755)
+		assertResult(err, "Couldn't create addons folder!")
+
+		// Copy files
+		sourcePath := filepath.Join(root, ".gddon.d", package_.Name, link.SourceFolder)
+		err = copyDir(sourcePath, targetPath)
+		assertResult(err, "Couldn't copy files to addons!")
+	}
+}
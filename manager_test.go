@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/wwqdrh/gddon/source"
+)
+
+// fakeFetcher records every Fetch call instead of touching the network, so
+// Manager logic can be tested without a real git remote or HTTP server.
+type fakeFetcher struct {
+	calls    []fakeFetchCall
+	resolved source.Version
+}
+
+type fakeFetchCall struct {
+	location string
+	destPath string
+	pinned   *source.Version
+}
+
+func (f *fakeFetcher) Fetch(location, destPath string, pinned *source.Version) (source.Version, error) {
+	f.calls = append(f.calls, fakeFetchCall{location: location, destPath: destPath, pinned: pinned})
+	if pinned != nil {
+		return *pinned, nil
+	}
+	return f.resolved, nil
+}
+
+// TestResolveLockSkipsSourcelessPackages guards against the crash where a
+// `gddon create`d local addon (Source == "") was fetched like any other
+// git remote and failed with "remote not found", since CreateAddon never
+// configures an origin for it.
+func TestResolveLockSkipsSourcelessPackages(t *testing.T) {
+	fetcher := &fakeFetcher{resolved: source.Version{Commit: "latest"}}
+	m := &Manager{Fetchers: source.Fetchers{source.SchemeGit: fetcher}}
+
+	gddonObject := &GddonObject{Packages: []GddonPackage{
+		{Name: "local-addon", Source: ""},
+	}}
+
+	lock := m.resolveLock("/project", gddonObject)
+
+	if len(fetcher.calls) != 0 {
+		t.Fatalf("expected no Fetch calls for a source-less package, got %+v", fetcher.calls)
+	}
+	if len(lock.Packages) != 1 || lock.Packages[0].Name != "local-addon" {
+		t.Fatalf("expected local-addon in the lock, got %+v", lock.Packages)
+	}
+}
+
+// TestResolveLockHonorsExistingPin guards against a first-time resolve (no
+// .gddon.lock yet) silently ignoring a Version already pinned in .gddon and
+// fetching latest instead.
+func TestResolveLockHonorsExistingPin(t *testing.T) {
+	fetcher := &fakeFetcher{resolved: source.Version{Commit: "latest"}}
+	m := &Manager{Fetchers: source.Fetchers{source.SchemeGit: fetcher}}
+
+	gddonObject := &GddonObject{Packages: []GddonPackage{
+		{Name: "pinned", Source: "git+https://example.com/repo.git", Version: source.Version{Commit: "abc123"}},
+	}}
+
+	lock := m.resolveLock("/project", gddonObject)
+
+	if len(fetcher.calls) != 1 {
+		t.Fatalf("expected exactly one Fetch call, got %+v", fetcher.calls)
+	}
+	if got := fetcher.calls[0].pinned; got == nil || got.Commit != "abc123" {
+		t.Fatalf("expected the existing pin %q to be passed through, got %+v", "abc123", got)
+	}
+	if lock.Packages[0].Version.Commit != "abc123" {
+		t.Fatalf("expected the lock to keep the pinned commit, got %q", lock.Packages[0].Version.Commit)
+	}
+}
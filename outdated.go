@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wwqdrh/gddon/source"
+)
+
+// OutdatedPackage reports how far a single package's pin has drifted from
+// upstream.
+type OutdatedPackage struct {
+	Name          string    `json:"name"`
+	Pinned        string    `json:"pinned"`
+	Latest        string    `json:"latest"`
+	CommitsBehind int       `json:"commits_behind"`
+	LastUpdate    time.Time `json:"last_update,omitempty"`
+	// Log holds the short log ("<short hash> <subject>") of the commits
+	// the pin is behind, newest first. Only populated for git sources.
+	Log []string `json:"log,omitempty"`
+}
+
+// CheckOutdated reports, per package, how far behind upstream its pin is.
+// Git sources are compared by commit count via origin/HEAD; archive
+// sources (HTTP/S3/GCS) are compared by re-downloading and diffing
+// sha256. It returns true if anything is outdated, which `gddon outdated`
+// turns into a non-zero exit code so it can gate CI.
+func (m *Manager) CheckOutdated(root string, verbose bool, jsonOutput bool) bool {
+	gddonFilePath := filepath.Join(root, ".gddon")
+	gddonObject := readGddonFile(gddonFilePath)
+
+	reports := make([]OutdatedPackage, 0, len(gddonObject.Packages))
+	anyOutdated := false
+
+	for _, pkg := range gddonObject.Packages {
+		report, outdated, err := m.checkPackageOutdated(root, pkg)
+		assertResult(err, fmt.Sprintf("Couldn't check %q for updates!", pkg.Name))
+		reports = append(reports, report)
+		if outdated {
+			anyOutdated = true
+		}
+	}
+
+	if jsonOutput {
+		printOutdatedJSON(reports)
+	} else {
+		printOutdatedTable(reports, verbose)
+	}
+
+	return anyOutdated
+}
+
+func (m *Manager) checkPackageOutdated(root string, pkg GddonPackage) (OutdatedPackage, bool, error) {
+	if pkg.Source == "" {
+		// A `gddon create`d local addon has no remote to compare against.
+		pin := pkg.Version.String()
+		return OutdatedPackage{Name: pkg.Name, Pinned: pin, Latest: pin}, false, nil
+	}
+
+	packagePath := filepath.Join(root, ".gddon.d", pkg.Name)
+
+	scheme, location, err := source.Parse(pkg.Source)
+	if err != nil {
+		return OutdatedPackage{}, false, err
+	}
+
+	if scheme != source.SchemeGit {
+		return m.checkArchiveOutdated(pkg, scheme, location)
+	}
+
+	if err := m.Backend.Fetch(packagePath); err != nil {
+		return OutdatedPackage{}, false, err
+	}
+
+	remoteHead, err := m.Backend.RemoteHead(packagePath)
+	if err != nil {
+		return OutdatedPackage{}, false, err
+	}
+
+	report := OutdatedPackage{Name: pkg.Name, Pinned: pkg.Version.Commit, Latest: remoteHead}
+
+	if remoteHead == pkg.Version.Commit {
+		return report, false, nil
+	}
+
+	commits, err := m.Backend.CommitsBetween(packagePath, pkg.Version.Commit, remoteHead)
+	if err != nil {
+		return OutdatedPackage{}, false, err
+	}
+
+	report.CommitsBehind = len(commits)
+	if len(commits) > 0 {
+		report.LastUpdate = commits[0].When
+	}
+	report.Log = make([]string, len(commits))
+	for i, c := range commits {
+		report.Log[i] = fmt.Sprintf("%s %s", shortRef(c.Hash), firstLine(c.Message))
+	}
+	return report, report.CommitsBehind > 0, nil
+}
+
+func (m *Manager) checkArchiveOutdated(pkg GddonPackage, scheme source.Scheme, location string) (OutdatedPackage, bool, error) {
+	fetcher, err := m.Fetchers.For(scheme)
+	if err != nil {
+		return OutdatedPackage{}, false, err
+	}
+
+	tempDir, err := os.MkdirTemp("", "gddon-outdated-*")
+	if err != nil {
+		return OutdatedPackage{}, false, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	latest, err := fetcher.Fetch(location, tempDir, nil)
+	if err != nil {
+		return OutdatedPackage{}, false, err
+	}
+
+	report := OutdatedPackage{
+		Name:   pkg.Name,
+		Pinned: pkg.Version.String(),
+		Latest: latest.String(),
+	}
+	outdated := latest.Sha256 != pkg.Version.Sha256
+	if outdated {
+		report.CommitsBehind = 1
+	}
+	return report, outdated, nil
+}
+
+func printOutdatedJSON(reports []OutdatedPackage) {
+	data, err := json.MarshalIndent(reports, "", "  ")
+	assertResult(err, "Couldn't marshal outdated report!")
+	fmt.Println(string(data))
+}
+
+func printOutdatedTable(reports []OutdatedPackage, verbose bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPINNED\tLATEST\tCOMMITS BEHIND\tLAST UPDATE")
+	for _, r := range reports {
+		lastUpdate := "-"
+		if !r.LastUpdate.IsZero() {
+			lastUpdate = r.LastUpdate.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", r.Name, shortRef(r.Pinned), shortRef(r.Latest), r.CommitsBehind, lastUpdate)
+	}
+	w.Flush()
+
+	if !verbose {
+		return
+	}
+	for _, r := range reports {
+		if len(r.Log) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", r.Name)
+		for _, line := range r.Log {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+// shortRef shortens a git commit hash for display; other pin kinds (tags,
+// sha256 archive hashes) are left untouched.
+func shortRef(ref string) string {
+	if len(ref) == 40 {
+		return ref[:7]
+	}
+	return ref
+}
+
+// firstLine returns the subject line of a commit message, discarding any
+// body.
+func firstLine(message string) string {
+	if i := strings.IndexByte(message, '\n'); i != -1 {
+		message = message[:i]
+	}
+	return strings.TrimSpace(message)
+}
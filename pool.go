@@ -0,0 +1,42 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Pool runs a bounded number of install jobs concurrently so a single
+// slow fetch doesn't block the rest of the install.
+type Pool struct {
+	sem chan struct{}
+}
+
+// NewPool builds a Pool that runs at most jobs goroutines at a time.
+// jobs <= 0 defaults to runtime.NumCPU().
+func NewPool(jobs int) *Pool {
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	return &Pool{sem: make(chan struct{}, jobs)}
+}
+
+// Run executes each job concurrently, bounded by the pool's size, and
+// blocks until all of them finish. The returned slice has one entry per
+// job, in the same order, nil where that job succeeded.
+func (p *Pool) Run(jobs []func() error) []error {
+	errs := make([]error, len(jobs))
+
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		p.sem <- struct{}{}
+		go func(i int, job func() error) {
+			defer wg.Done()
+			defer func() { <-p.sem }()
+			errs[i] = job()
+		}(i, job)
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// promptText prompts the user for text input, returning defaultValue if
+// they submit a blank answer.
+func promptText(prompt string, defaultValue string) string {
+	var input string
+	question := &survey.Input{Message: prompt, Default: defaultValue}
+	if err := survey.AskOne(question, &input); err != nil {
+		logError(err.Error())
+		os.Exit(1)
+	}
+	return input
+}
+
+// promptSelect prompts the user to pick exactly one of options.
+func promptSelect(prompt string, options []string) string {
+	var answer string
+	question := &survey.Select{Message: prompt, Options: options}
+	if err := survey.AskOne(question, &answer); err != nil {
+		logError(err.Error())
+		os.Exit(1)
+	}
+	return answer
+}
+
+// promptMultiSelect prompts the user to pick any subset of options.
+func promptMultiSelect(prompt string, options []string) []string {
+	var answers []string
+	question := &survey.MultiSelect{Message: prompt, Options: options}
+	if err := survey.AskOne(question, &answers); err != nil {
+		logError(err.Error())
+		os.Exit(1)
+	}
+	return answers
+}
+
+// promptConfirm asks a yes/no question, returning defaultValue if the
+// user just presses enter.
+func promptConfirm(prompt string, defaultValue bool) bool {
+	answer := defaultValue
+	question := &survey.Confirm{Message: prompt, Default: defaultValue}
+	if err := survey.AskOne(question, &answer); err != nil {
+		logError(err.Error())
+		os.Exit(1)
+	}
+	return answer
+}
@@ -0,0 +1,40 @@
+package resolve
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Dependency declares one transitive addon dependency inside an addon's
+// gddon.json.
+type Dependency struct {
+	Name              string `json:"name"`
+	Source            string `json:"source"`
+	VersionConstraint string `json:"version_constraint"`
+}
+
+// AddonManifest is the gddon.json schema addon repos may ship at their
+// root to declare their own dependencies. Addons without one are treated
+// as having no dependencies.
+type AddonManifest struct {
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// LoadManifest reads gddon.json from addonPath. A missing file is not an
+// error: most addons have no transitive dependencies.
+func LoadManifest(addonPath string) (AddonManifest, error) {
+	data, err := os.ReadFile(filepath.Join(addonPath, "gddon.json"))
+	if os.IsNotExist(err) {
+		return AddonManifest{}, nil
+	}
+	if err != nil {
+		return AddonManifest{}, err
+	}
+
+	var manifest AddonManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return AddonManifest{}, err
+	}
+	return manifest, nil
+}
@@ -0,0 +1,170 @@
+// Package resolve builds the transitive dependency graph for a project's
+// addons, producing a flat, conflict-free, exactly-pinned lockfile.
+package resolve
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/wwqdrh/gddon/source"
+)
+
+// Requirement is one edge in the dependency graph: "name, fetched from
+// source, constrained to versionConstraint". The root manifest's packages
+// and every addon's gddon.json dependencies are both expressed this way.
+type Requirement struct {
+	Name              string
+	Source            string
+	VersionConstraint string
+}
+
+// LockedPackage is one fully-resolved entry in .gddon.lock.
+type LockedPackage struct {
+	Name    string         `json:"name"`
+	Source  string         `json:"source"`
+	Version source.Version `json:"version"`
+}
+
+// Lockfile is the .gddon.lock contents: every root and transitive
+// dependency, flattened, with an exact version for each.
+type Lockfile struct {
+	Packages []LockedPackage `json:"packages"`
+}
+
+// Fetch is called once per distinct (name, source) pair the resolver
+// discovers. It must make the addon available on disk (reusing an
+// existing checkout when possible), and return the manifestDir to read
+// gddon.json from plus the Version it resolved to.
+type Fetch func(req Requirement) (manifestDir string, resolved source.Version, err error)
+
+type node struct {
+	req       Requirement
+	resolved  source.Version
+	manifest  string
+	requiredBy map[string][]string // constraint -> list of requirers, for conflict messages
+}
+
+// Resolve performs a breadth-first walk of root and its transitive
+// dependencies, unifying version constraints per package name and
+// detecting both cycles and unsatisfiable constraint conflicts.
+func Resolve(root []Requirement, fetch Fetch) (*Lockfile, error) {
+	nodes := map[string]*node{}
+	order := []string{}
+
+	type queued struct {
+		req    Requirement
+		parent string
+		// ancestors holds every name on the path from a root requirement
+		// down to (and including) parent, so a cycle can be detected the
+		// moment a name reappears on its own branch, regardless of
+		// whether it was already dequeued elsewhere (a diamond
+		// dependency, which is fine).
+		ancestors map[string]bool
+	}
+
+	queue := make([]queued, 0, len(root))
+	for _, r := range root {
+		queue = append(queue, queued{req: r, parent: "<root>", ancestors: map[string]bool{}})
+	}
+
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		if item.ancestors[item.req.Name] {
+			return nil, fmt.Errorf("dependency cycle detected at %q", item.req.Name)
+		}
+
+		n, seen := nodes[item.req.Name]
+		if !seen {
+			n = &node{req: item.req, requiredBy: map[string][]string{}}
+			nodes[item.req.Name] = n
+			order = append(order, item.req.Name)
+		} else if n.req.Source != item.req.Source {
+			return nil, fmt.Errorf("dependency conflict on %q: %s requires source %q, but it is also required from %q",
+				item.req.Name, item.parent, item.req.Source, n.req.Source)
+		}
+		n.requiredBy[item.req.VersionConstraint] = append(n.requiredBy[item.req.VersionConstraint], item.parent)
+
+		if seen {
+			continue
+		}
+
+		manifestDir, resolved, err := fetch(item.req)
+		if err != nil {
+			return nil, fmt.Errorf("fetch %q: %w", item.req.Name, err)
+		}
+		n.manifest = manifestDir
+		n.resolved = resolved
+
+		manifest, err := LoadManifest(manifestDir)
+		if err != nil {
+			return nil, fmt.Errorf("read gddon.json for %q: %w", item.req.Name, err)
+		}
+
+		childAncestors := make(map[string]bool, len(item.ancestors)+1)
+		for name := range item.ancestors {
+			childAncestors[name] = true
+		}
+		childAncestors[item.req.Name] = true
+
+		for _, dep := range manifest.Dependencies {
+			queue = append(queue, queued{
+				req:       Requirement{Name: dep.Name, Source: dep.Source, VersionConstraint: dep.VersionConstraint},
+				parent:    item.req.Name,
+				ancestors: childAncestors,
+			})
+		}
+	}
+
+	lock := &Lockfile{}
+	for _, name := range order {
+		n := nodes[name]
+		if err := unifyConstraints(name, n); err != nil {
+			return nil, err
+		}
+		lock.Packages = append(lock.Packages, LockedPackage{
+			Name:    name,
+			Source:  n.req.Source,
+			Version: n.resolved,
+		})
+	}
+
+	return lock, nil
+}
+
+// unifyConstraints checks that the resolved version for n satisfies every
+// semver constraint that named it as a dependency, producing an error
+// like `A requires X@^1, B requires X@^2` when two requirers disagree.
+func unifyConstraints(name string, n *node) error {
+	if n.resolved.Tag == "" {
+		return nil // git-commit / sha256 pins have no semver range to check
+	}
+
+	resolvedVer, err := semver.NewVersion(n.resolved.Tag)
+	if err != nil {
+		return nil // not a semver tag, nothing to unify
+	}
+
+	for constraintStr, requirers := range n.requiredBy {
+		if constraintStr == "" {
+			continue
+		}
+		constraint, err := semver.NewConstraint(constraintStr)
+		if err != nil {
+			return fmt.Errorf("invalid version constraint %q for %q: %w", constraintStr, name, err)
+		}
+		if !constraint.Check(resolvedVer) {
+			other := fmt.Sprintf("resolved to %s", n.resolved.Tag)
+			for otherConstraint, otherRequirers := range n.requiredBy {
+				if otherConstraint != constraintStr {
+					other = fmt.Sprintf("%s requires %s@%s", otherRequirers[0], name, otherConstraint)
+					break
+				}
+			}
+			return fmt.Errorf("version conflict on %q: %s requires %s@%s, %s",
+				name, requirers[0], name, constraintStr, other)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package resolve
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wwqdrh/gddon/source"
+)
+
+// writeManifests materializes each manifest as <tmp>/<name>/gddon.json, the
+// same layout Resolve expects Fetch to hand it back via manifestDir, and
+// returns a Fetch that looks requirements up by name.
+func writeManifests(t *testing.T, manifests map[string]string, versions map[string]string) Fetch {
+	t.Helper()
+	tmp := t.TempDir()
+
+	for name, manifestJSON := range manifests {
+		dir := filepath.Join(tmp, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "gddon.json"), []byte(manifestJSON), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return func(req Requirement) (string, source.Version, error) {
+		tag := versions[req.Name]
+		if tag == "" {
+			tag = "1.0.0"
+		}
+		return filepath.Join(tmp, req.Name), source.Version{Tag: tag}, nil
+	}
+}
+
+func TestResolveDetectsCycle(t *testing.T) {
+	fetch := writeManifests(t, map[string]string{
+		"A": `{"dependencies":[{"name":"B","source":"b"}]}`,
+		"B": `{"dependencies":[{"name":"A","source":"a"}]}`,
+	}, nil)
+
+	_, err := Resolve([]Requirement{{Name: "A", Source: "a"}}, fetch)
+	if err == nil {
+		t.Fatal("expected a cycle error, got nil")
+	}
+	if !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected a cycle error, got %q", err)
+	}
+}
+
+func TestResolveAllowsDiamondDependency(t *testing.T) {
+	fetch := writeManifests(t, map[string]string{
+		"X": `{"dependencies":[{"name":"Z","source":"z"}]}`,
+		"Y": `{"dependencies":[{"name":"Z","source":"z"}]}`,
+		"Z": `{"dependencies":[]}`,
+	}, nil)
+
+	lock, err := Resolve([]Requirement{{Name: "X", Source: "x"}, {Name: "Y", Source: "y"}}, fetch)
+	if err != nil {
+		t.Fatalf("diamond dependency should resolve cleanly, got %v", err)
+	}
+	if len(lock.Packages) != 3 {
+		t.Fatalf("expected 3 packages (X, Y, Z), got %d: %+v", len(lock.Packages), lock.Packages)
+	}
+}
+
+func TestResolveDetectsSourceConflict(t *testing.T) {
+	fetch := writeManifests(t, map[string]string{
+		"A":      `{"dependencies":[{"name":"shared","source":"repo-one"}]}`,
+		"B":      `{"dependencies":[{"name":"shared","source":"repo-two"}]}`,
+		"shared": `{"dependencies":[]}`,
+	}, nil)
+
+	_, err := Resolve([]Requirement{{Name: "A", Source: "a"}, {Name: "B", Source: "b"}}, fetch)
+	if err == nil {
+		t.Fatal("expected a source conflict error, got nil")
+	}
+	if !strings.Contains(err.Error(), "conflict") {
+		t.Fatalf("expected a conflict error, got %q", err)
+	}
+}
+
+func TestResolveDetectsVersionConflict(t *testing.T) {
+	fetch := writeManifests(t, map[string]string{
+		"A":      `{"dependencies":[{"name":"shared","source":"s","version_constraint":"^1.0.0"}]}`,
+		"B":      `{"dependencies":[{"name":"shared","source":"s","version_constraint":"^2.0.0"}]}`,
+		"shared": `{"dependencies":[]}`,
+	}, map[string]string{"shared": "1.5.0"})
+
+	_, err := Resolve([]Requirement{{Name: "A", Source: "a"}, {Name: "B", Source: "b"}}, fetch)
+	if err == nil {
+		t.Fatal("expected a version conflict error, got nil")
+	}
+	if !strings.Contains(err.Error(), "version conflict") {
+		t.Fatalf("expected a version conflict error, got %q", err)
+	}
+}
@@ -0,0 +1,72 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/storage"
+)
+
+// GCSFetcher downloads an object from a "gs://bucket/key.zip" location
+// using application-default credentials, then extracts it the same way
+// HTTPFetcher does.
+type GCSFetcher struct {
+	Client *storage.Client
+}
+
+func (f *GCSFetcher) client(ctx context.Context) (*storage.Client, error) {
+	if f.Client != nil {
+		return f.Client, nil
+	}
+	return storage.NewClient(ctx)
+}
+
+func (f *GCSFetcher) Fetch(location string, destPath string, pinned *Version) (Version, error) {
+	bucket, key, err := parseBucketURL(location, "gs://")
+	if err != nil {
+		return Version{}, err
+	}
+
+	ctx := context.Background()
+	client, err := f.client(ctx)
+	if err != nil {
+		return Version{}, err
+	}
+	defer client.Close()
+
+	reader, err := client.Bucket(bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		return Version{}, fmt.Errorf("get gs://%s/%s: %w", bucket, key, err)
+	}
+	defer reader.Close()
+
+	archivePath, sum, err := downloadToTemp(reader)
+	if err != nil {
+		return Version{}, err
+	}
+	defer os.Remove(archivePath)
+
+	if pinned != nil && pinned.Sha256 != "" && pinned.Sha256 != sum {
+		return Version{}, fmt.Errorf("source %s: sha256 mismatch, expected %s got %s", location, pinned.Sha256, sum)
+	}
+
+	if err := os.RemoveAll(destPath); err != nil {
+		return Version{}, err
+	}
+	if err := os.MkdirAll(destPath, 0755); err != nil {
+		return Version{}, err
+	}
+
+	if strings.HasSuffix(key, ".zip") {
+		err = extractZip(archivePath, destPath)
+	} else {
+		err = extractTarGz(archivePath, destPath)
+	}
+	if err != nil {
+		return Version{}, err
+	}
+
+	return Version{Sha256: sum}, nil
+}
@@ -0,0 +1,86 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/wwqdrh/gddon/vcs"
+)
+
+// GitFetcher fetches a package from a clonable git remote, delegating the
+// actual repo operations to a vcs.Backend so it shares behavior with the
+// rest of the manager.
+type GitFetcher struct {
+	Backend vcs.Backend
+
+	// UseMirrors, when true, asks GitFetcher to derive each checkout from
+	// a single shared bare mirror per remote (kept at "<destPath's
+	// parent>/.mirrors") instead of cloning the remote fresh for every
+	// package, so InstallRepositories can fetch many packages concurrently
+	// without N full clones of the same upstream repo. This only takes
+	// effect when Backend also implements vcs.MirrorBackend; otherwise
+	// Fetch falls back to a plain clone-or-fetch.
+	UseMirrors bool
+}
+
+func (f *GitFetcher) Fetch(location string, destPath string, pinned *Version) (Version, error) {
+	if f.UseMirrors {
+		if mirrorBackend, ok := f.Backend.(vcs.MirrorBackend); ok {
+			return f.fetchViaMirror(mirrorBackend, location, destPath, pinned)
+		}
+	}
+	return f.fetchDirect(location, destPath, pinned)
+}
+
+func (f *GitFetcher) fetchDirect(location string, destPath string, pinned *Version) (Version, error) {
+	if _, err := os.Stat(destPath); os.IsNotExist(err) {
+		if err := f.Backend.Clone(location, destPath); err != nil {
+			return Version{}, err
+		}
+	} else {
+		if err := f.Backend.Fetch(destPath); err != nil {
+			return Version{}, err
+		}
+	}
+
+	if pinned != nil && pinned.Commit != "" {
+		if err := f.Backend.Checkout(destPath, pinned.Commit); err != nil {
+			return Version{}, err
+		}
+		return *pinned, nil
+	}
+
+	head, err := f.Backend.HeadCommit(destPath)
+	if err != nil {
+		return Version{}, err
+	}
+	return Version{Commit: head}, nil
+}
+
+func (f *GitFetcher) fetchViaMirror(backend vcs.MirrorBackend, location string, destPath string, pinned *Version) (Version, error) {
+	mirrorRoot := filepath.Join(filepath.Dir(destPath), ".mirrors")
+
+	mirrorPath, err := backend.EnsureMirror(location, mirrorRoot)
+	if err != nil {
+		return Version{}, err
+	}
+
+	commitish := ""
+	if pinned != nil && pinned.Commit != "" {
+		commitish = pinned.Commit
+	}
+
+	if err := backend.CheckoutWorktree(mirrorPath, destPath, commitish); err != nil {
+		return Version{}, err
+	}
+
+	if pinned != nil && pinned.Commit != "" {
+		return *pinned, nil
+	}
+
+	head, err := backend.HeadCommit(destPath)
+	if err != nil {
+		return Version{}, err
+	}
+	return Version{Commit: head}, nil
+}
@@ -0,0 +1,185 @@
+package source
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HTTPFetcher downloads a tar.gz or zip archive over plain HTTP(S),
+// verifies (or records) its sha256, and extracts it into destPath. There
+// is no commit to pin to, so the sha256 of the archive itself is the
+// reproducible pin.
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+func (f *HTTPFetcher) client() *http.Client {
+	if f.Client != nil {
+		return f.Client
+	}
+	return http.DefaultClient
+}
+
+func (f *HTTPFetcher) Fetch(location string, destPath string, pinned *Version) (Version, error) {
+	archivePath, sum, err := f.download(location)
+	if err != nil {
+		return Version{}, err
+	}
+	defer os.Remove(archivePath)
+
+	if pinned != nil && pinned.Sha256 != "" && pinned.Sha256 != sum {
+		return Version{}, fmt.Errorf("source %s: sha256 mismatch, expected %s got %s", location, pinned.Sha256, sum)
+	}
+
+	if err := os.RemoveAll(destPath); err != nil {
+		return Version{}, err
+	}
+	if err := os.MkdirAll(destPath, 0755); err != nil {
+		return Version{}, err
+	}
+
+	switch {
+	case strings.HasSuffix(location, ".zip"):
+		err = extractZip(archivePath, destPath)
+	default:
+		err = extractTarGz(archivePath, destPath)
+	}
+	if err != nil {
+		return Version{}, err
+	}
+
+	return Version{Sha256: sum}, nil
+}
+
+// download fetches location into a temp file and returns its path
+// alongside the hex-encoded sha256 of its contents.
+func (f *HTTPFetcher) download(location string) (string, string, error) {
+	resp, err := f.client().Get(location)
+	if err != nil {
+		return "", "", fmt.Errorf("download %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("download %s: unexpected status %s", location, resp.Status)
+	}
+
+	path, sum, err := downloadToTemp(resp.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("download %s: %w", location, err)
+	}
+	return path, sum, nil
+}
+
+// safeJoin joins destPath with an archive entry's name and guarantees the
+// result stays inside destPath, rejecting "../" traversal that would escape
+// it (Zip Slip) in a crafted or compromised archive. An absolute entry
+// name is not rejected: it is joined under destPath like a relative one.
+func safeJoin(destPath, name string) (string, error) {
+	target := filepath.Join(destPath, name)
+	if target != destPath && !strings.HasPrefix(target, destPath+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %q escapes destination %q", name, destPath)
+	}
+	return target, nil
+}
+
+func extractTarGz(archivePath, destPath string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("open tar.gz: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := safeJoin(destPath, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			out.Close()
+		}
+	}
+}
+
+func extractZip(archivePath, destPath string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("open zip: %w", err)
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		target, err := safeJoin(destPath, file.Name)
+		if err != nil {
+			return err
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, file.Mode()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, file.Mode())
+		if err != nil {
+			src.Close()
+			return err
+		}
+
+		_, err = io.Copy(out, src)
+		src.Close()
+		out.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
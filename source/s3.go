@@ -0,0 +1,112 @@
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Fetcher downloads an object from an "s3://bucket/key.zip" location
+// using the default AWS SDK credential chain, then extracts it the same
+// way HTTPFetcher does.
+type S3Fetcher struct {
+	Client *s3.Client
+}
+
+func (f *S3Fetcher) client(ctx context.Context) (*s3.Client, error) {
+	if f.Client != nil {
+		return f.Client, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	return s3.NewFromConfig(cfg), nil
+}
+
+func (f *S3Fetcher) Fetch(location string, destPath string, pinned *Version) (Version, error) {
+	bucket, key, err := parseBucketURL(location, "s3://")
+	if err != nil {
+		return Version{}, err
+	}
+
+	ctx := context.Background()
+	client, err := f.client(ctx)
+	if err != nil {
+		return Version{}, err
+	}
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return Version{}, fmt.Errorf("get s3://%s/%s: %w", bucket, key, err)
+	}
+	defer out.Body.Close()
+
+	archivePath, sum, err := downloadToTemp(out.Body)
+	if err != nil {
+		return Version{}, err
+	}
+	defer os.Remove(archivePath)
+
+	if pinned != nil && pinned.Sha256 != "" && pinned.Sha256 != sum {
+		return Version{}, fmt.Errorf("source %s: sha256 mismatch, expected %s got %s", location, pinned.Sha256, sum)
+	}
+
+	if err := os.RemoveAll(destPath); err != nil {
+		return Version{}, err
+	}
+	if err := os.MkdirAll(destPath, 0755); err != nil {
+		return Version{}, err
+	}
+
+	if strings.HasSuffix(key, ".zip") {
+		err = extractZip(archivePath, destPath)
+	} else {
+		err = extractTarGz(archivePath, destPath)
+	}
+	if err != nil {
+		return Version{}, err
+	}
+
+	return Version{Sha256: sum}, nil
+}
+
+// parseBucketURL splits a "scheme://bucket/key" location into its bucket
+// and key parts.
+func parseBucketURL(location, scheme string) (bucket string, key string, err error) {
+	rest := strings.TrimPrefix(location, scheme)
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("source %q: expected %sbucket/key", location, scheme)
+	}
+	return parts[0], parts[1], nil
+}
+
+// downloadToTemp copies r into a temp file, returning its path and the
+// hex-encoded sha256 of its contents.
+func downloadToTemp(r io.Reader) (string, string, error) {
+	tmp, err := os.CreateTemp("", "gddon-archive-*")
+	if err != nil {
+		return "", "", err
+	}
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hasher), r); err != nil {
+		return "", "", err
+	}
+
+	return tmp.Name(), hex.EncodeToString(hasher.Sum(nil)), nil
+}
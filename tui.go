@@ -0,0 +1,192 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	tuiActionUpdate   = "Update"
+	tuiActionApply    = "Apply (project -> repository)"
+	tuiActionViewDiff = "View diff (project vs repository)"
+	tuiActionRemove   = "Remove"
+	tuiActionQuit     = "Quit"
+)
+
+// RunTUI lists every installed package and lets the user repeatedly pick
+// one plus an action (update, apply, view-diff, remove) without having to
+// remember the equivalent flat CLI invocation for each.
+func (m *Manager) RunTUI(root string, verbose bool) {
+	for {
+		gddonFilePath := filepath.Join(root, ".gddon")
+		gddonObject := readGddonFile(gddonFilePath)
+
+		if len(gddonObject.Packages) == 0 {
+			logInfo("No addons installed yet.")
+			return
+		}
+
+		options := make([]string, len(gddonObject.Packages))
+		for i, pkg := range gddonObject.Packages {
+			options[i] = fmt.Sprintf("%s (%s)", pkg.Name, pkg.Version.String())
+		}
+		options = append(options, tuiActionQuit)
+
+		choice := promptSelect("Select a package", options)
+		if choice == tuiActionQuit {
+			return
+		}
+
+		packageIndex := len(options) - 1
+		for i, option := range options {
+			if option == choice {
+				packageIndex = i
+				break
+			}
+		}
+		targetPackage := &gddonObject.Packages[packageIndex]
+
+		action := promptSelect(fmt.Sprintf("What do you want to do with %s?", targetPackage.Name),
+			[]string{tuiActionUpdate, tuiActionApply, tuiActionViewDiff, tuiActionRemove, tuiActionQuit})
+
+		switch action {
+		case tuiActionUpdate:
+			m.fetchPackage(root, targetPackage, nil)
+			m.installGddonPackage(root, targetPackage)
+			writeGddonFile(gddonFilePath, &gddonObject)
+			lock := m.resolveLock(root, &gddonObject)
+			writeLockFile(lockFilePath(root), lock)
+		case tuiActionApply:
+			applyPackageFiles(root, targetPackage, verbose)
+			writeGddonFile(gddonFilePath, &gddonObject)
+		case tuiActionViewDiff:
+			printPackageDiff(root, targetPackage)
+		case tuiActionRemove:
+			if promptConfirm(fmt.Sprintf("Really remove %s?", targetPackage.Name), false) {
+				m.RemovePackage(root, targetPackage.Name)
+			}
+		case tuiActionQuit:
+			return
+		}
+	}
+}
+
+// RemovePackage drops a package from .gddon, deletes its .gddon.d/<name>
+// checkout, and regenerates the lockfile.
+func (m *Manager) RemovePackage(root string, name string) {
+	gddonFilePath := filepath.Join(root, ".gddon")
+	gddonObject := readGddonFile(gddonFilePath)
+
+	packageIndex := findPackageByName(gddonObject.Packages, name)
+	if packageIndex == -1 {
+		logError(fmt.Sprintf("No such package %q", name))
+		return
+	}
+
+	gddonObject.Packages = append(gddonObject.Packages[:packageIndex], gddonObject.Packages[packageIndex+1:]...)
+	writeGddonFile(gddonFilePath, &gddonObject)
+
+	err := os.RemoveAll(filepath.Join(root, ".gddon.d", name))
+	assertResult(err, fmt.Sprintf("Couldn't remove .gddon.d/%s!", name))
+
+	lock := m.resolveLock(root, &gddonObject)
+	writeLockFile(lockFilePath(root), lock)
+
+	logCheck(fmt.Sprintf("Removed %s", name))
+}
+
+// printPackageDiff prints every file that was added, removed, or changed
+// between a package's checkout and the project files it's linked to.
+func printPackageDiff(root string, package_ *GddonPackage) {
+	for _, link := range package_.Links {
+		sourcePath := filepath.Join(root, ".gddon.d", package_.Name, link.SourceFolder)
+		targetPath := filepath.Join(root, link.TargetFolder)
+
+		diffs, err := diffDirs(sourcePath, targetPath)
+		assertResult(err, "Couldn't diff package files!")
+
+		if len(diffs) == 0 {
+			logInfo(fmt.Sprintf("%s: no differences", link.TargetFolder))
+			continue
+		}
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+	}
+}
+
+// diffDirs compares two directory trees file-by-file via sha256 and
+// returns one line per added ("+"), removed ("-"), or modified ("~")
+// file, relative to the trees' roots.
+func diffDirs(a, b string) ([]string, error) {
+	aFiles, err := listFilesRecursive(a)
+	if err != nil {
+		return nil, err
+	}
+	bFiles, err := listFilesRecursive(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	for rel, aSum := range aFiles {
+		bSum, ok := bFiles[rel]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("- %s", rel))
+		case aSum != bSum:
+			diffs = append(diffs, fmt.Sprintf("~ %s", rel))
+		}
+	}
+	for rel := range bFiles {
+		if _, ok := aFiles[rel]; !ok {
+			diffs = append(diffs, fmt.Sprintf("+ %s", rel))
+		}
+	}
+	return diffs, nil
+}
+
+// listFilesRecursive maps every regular file under root (relative path)
+// to the hex-encoded sha256 of its contents.
+func listFilesRecursive(root string) (map[string]string, error) {
+	sums := map[string]string{}
+
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return sums, nil
+	}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		hasher := sha256.New()
+		if _, err := io.Copy(hasher, file); err != nil {
+			return err
+		}
+
+		sums[rel] = fmt.Sprintf("%x", hasher.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
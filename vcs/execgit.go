@@ -0,0 +1,181 @@
+package vcs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExecGitBackend shells out to the system "git" binary. It exists as a
+// fallback for users who need credential helpers, SSH agents or git
+// config that go-git does not support. Unlike the old ad-hoc commands,
+// arguments are passed directly to exec.Command instead of being
+// concatenated into a "sh -c" string, so it works without a shell.
+type ExecGitBackend struct{}
+
+func (b *ExecGitBackend) run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// runGitDir runs a git command against a specific --git-dir rather than a
+// working directory, which is what bare-mirror and worktree operations
+// need.
+func (b *ExecGitBackend) runGitDir(gitDir string, args ...string) (string, error) {
+	return b.run("", append([]string{"--git-dir", gitDir}, args...)...)
+}
+
+// mirrorPath returns the deterministic bare-mirror path for remoteURL under
+// root. Mirrors are named by content hash rather than repo name so two
+// differently-named packages pointing at the same remote share one.
+func mirrorPath(root, remoteURL string) string {
+	sum := sha256.Sum256([]byte(remoteURL))
+	return filepath.Join(root, hex.EncodeToString(sum[:])+".git")
+}
+
+func (b *ExecGitBackend) EnsureMirror(remoteURL, mirrorRoot string) (string, error) {
+	path := mirrorPath(mirrorRoot, remoteURL)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(mirrorRoot, 0755); err != nil {
+			return "", err
+		}
+		if _, err := b.run("", "clone", "--mirror", remoteURL, path); err != nil {
+			return "", fmt.Errorf("mirror clone %s: %w", remoteURL, err)
+		}
+		return path, nil
+	}
+
+	if _, err := b.runGitDir(path, "fetch", "--prune", "origin"); err != nil {
+		return "", fmt.Errorf("mirror fetch %s: %w", remoteURL, err)
+	}
+	return path, nil
+}
+
+func (b *ExecGitBackend) CheckoutWorktree(mirrorPath, worktreePath, commitish string) error {
+	// A worktree add fails if the target already exists, so clear any
+	// leftover from a prior failed or interrupted install first.
+	if err := b.removeWorktree(mirrorPath, worktreePath); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(worktreePath), 0755); err != nil {
+		return err
+	}
+
+	args := []string{"worktree", "add", "--detach", "--force", worktreePath}
+	if commitish != "" {
+		args = append(args, commitish)
+	}
+	if _, err := b.runGitDir(mirrorPath, args...); err != nil {
+		return fmt.Errorf("worktree add %s: %w", worktreePath, err)
+	}
+	return nil
+}
+
+// removeWorktree detaches worktreePath from mirrorPath (if attached) and
+// prunes stale worktree metadata so the mirror doesn't accumulate dead
+// entries.
+func (b *ExecGitBackend) removeWorktree(mirrorPath, worktreePath string) error {
+	if _, err := os.Stat(mirrorPath); err == nil {
+		// Best-effort: the worktree may not be registered yet.
+		b.runGitDir(mirrorPath, "worktree", "remove", "--force", worktreePath)
+	}
+
+	if err := os.RemoveAll(worktreePath); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(mirrorPath); err == nil {
+		if _, err := b.runGitDir(mirrorPath, "worktree", "prune"); err != nil {
+			return fmt.Errorf("worktree prune %s: %w", mirrorPath, err)
+		}
+	}
+	return nil
+}
+
+func (b *ExecGitBackend) Clone(remoteURL, path string) error {
+	_, err := b.run("", "clone", remoteURL, path)
+	return err
+}
+
+func (b *ExecGitBackend) Fetch(path string) error {
+	_, err := b.run(path, "fetch", "origin")
+	return err
+}
+
+func (b *ExecGitBackend) Checkout(path, commitish string) error {
+	_, err := b.run(path, "reset", "--hard", commitish)
+	return err
+}
+
+func (b *ExecGitBackend) HeadCommit(path string) (string, error) {
+	out, err := b.run(path, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (b *ExecGitBackend) Init(path string) error {
+	_, err := b.run(path, "init")
+	return err
+}
+
+func (b *ExecGitBackend) RemoteURL(path string) (string, error) {
+	out, err := b.run(path, "remote", "get-url", "origin")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (b *ExecGitBackend) RemoteHead(path string) (string, error) {
+	out, err := b.run(path, "rev-parse", "origin/HEAD")
+	if err != nil {
+		// Older remotes may not have origin/HEAD set locally; fall back
+		// to whatever branch is currently checked out.
+		out, err = b.run(path, "rev-parse", "@{u}")
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(out), nil
+}
+
+const commitLogFormat = "%H\x1f%ct\x1f%s"
+
+func (b *ExecGitBackend) CommitsBetween(path, from, to string) ([]Commit, error) {
+	out, err := b.run(path, "log", "--format="+commitLogFormat, fmt.Sprintf("%s..%s", from, to))
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		unix, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse commit date %q: %w", fields[1], err)
+		}
+		commits = append(commits, Commit{Hash: fields[0], When: time.Unix(unix, 0), Message: fields[2]})
+	}
+	return commits, nil
+}
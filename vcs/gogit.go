@@ -0,0 +1,154 @@
+package vcs
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// GoGitBackend implements Backend on top of github.com/go-git/go-git/v5.
+// It is the default backend: it needs no system git binary and works the
+// same way on Windows, macOS and Linux.
+type GoGitBackend struct{}
+
+func (b *GoGitBackend) Clone(remoteURL, path string) error {
+	_, err := git.PlainClone(path, false, &git.CloneOptions{
+		URL:      remoteURL,
+		Progress: nil,
+	})
+	if err != nil {
+		return fmt.Errorf("clone %s: %w", remoteURL, err)
+	}
+	return nil
+}
+
+func (b *GoGitBackend) Fetch(path string) error {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+
+	err = repo.Fetch(&git.FetchOptions{RemoteName: "origin"})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("fetch %s: %w", path, err)
+	}
+	return nil
+}
+
+func (b *GoGitBackend) Checkout(path, commitish string) error {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("worktree %s: %w", path, err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(commitish))
+	if err != nil {
+		return fmt.Errorf("checkout %s@%s: %w", path, commitish, err)
+	}
+
+	err = worktree.Checkout(&git.CheckoutOptions{
+		Hash:  *hash,
+		Force: true,
+	})
+	if err != nil {
+		return fmt.Errorf("checkout %s@%s: %w", path, commitish, err)
+	}
+	return nil
+}
+
+func (b *GoGitBackend) HeadCommit(path string) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("head %s: %w", path, err)
+	}
+	return head.Hash().String(), nil
+}
+
+func (b *GoGitBackend) Init(path string) error {
+	_, err := git.PlainInit(path, false)
+	if err != nil {
+		return fmt.Errorf("init %s: %w", path, err)
+	}
+	return nil
+}
+
+func (b *GoGitBackend) RemoteHead(path string) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+
+	ref, err := repo.Reference(plumbing.NewRemoteHEADReferenceName("origin"), true)
+	if err != nil {
+		// Some remotes don't advertise HEAD explicitly; fall back to the
+		// remote-tracking branch for whatever branch is checked out.
+		head, headErr := repo.Head()
+		if headErr != nil {
+			return "", fmt.Errorf("remote head %s: %w", path, err)
+		}
+		ref, err = repo.Reference(plumbing.NewRemoteReferenceName("origin", head.Name().Short()), true)
+		if err != nil {
+			return "", fmt.Errorf("remote head %s: %w", path, err)
+		}
+	}
+	return ref.Hash().String(), nil
+}
+
+func (b *GoGitBackend) CommitsBetween(path, from, to string) ([]Commit, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: plumbing.NewHash(to)})
+	if err != nil {
+		return nil, fmt.Errorf("log %s: %w", path, err)
+	}
+	defer iter.Close()
+
+	fromHash := plumbing.NewHash(from)
+	var commits []Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if c.Hash == fromHash {
+			return storer.ErrStop
+		}
+		commits = append(commits, Commit{Hash: c.Hash.String(), Message: c.Message, When: c.Author.When})
+		return nil
+	})
+	if err != nil && err != storer.ErrStop {
+		return nil, fmt.Errorf("log %s: %w", path, err)
+	}
+
+	return commits, nil
+}
+
+func (b *GoGitBackend) RemoteURL(path string) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return "", fmt.Errorf("remote origin %s: %w", path, err)
+	}
+
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", fmt.Errorf("remote origin %s has no URL", path)
+	}
+	return urls[0], nil
+}
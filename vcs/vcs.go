@@ -0,0 +1,84 @@
+// Package vcs abstracts the version control operations gddon needs
+// (clone, fetch, checkout, ...) behind a small interface so the manager
+// does not have to shell out to a specific git binary.
+package vcs
+
+import (
+	"fmt"
+	"time"
+)
+
+// Backend is implemented by anything capable of managing a git checkout
+// on disk. Implementations are free to use a library (go-git) or an
+// external executable, as long as they honor these semantics.
+type Backend interface {
+	// Clone clones remoteURL into path. path must not already exist.
+	Clone(remoteURL, path string) error
+	// Fetch fetches updates for the repository checked out at path.
+	Fetch(path string) error
+	// Checkout resets the repository at path to the given commit-ish.
+	Checkout(path, commitish string) error
+	// HeadCommit returns the full commit hash currently checked out at path.
+	HeadCommit(path string) (string, error)
+	// Init creates a brand new repository at path.
+	Init(path string) error
+	// RemoteURL returns the URL configured for "origin" at path.
+	RemoteURL(path string) (string, error)
+	// RemoteHead returns the commit hash that origin/HEAD currently
+	// points to for the repository at path. Fetch should be called first
+	// to make sure it reflects the latest upstream state.
+	RemoteHead(path string) (string, error)
+	// CommitsBetween returns the log of commits reachable from "to" but
+	// not from "from" (i.e. `git log from..to`), oldest last, so callers
+	// can report how many commits a pin is behind and what they were.
+	CommitsBetween(path, from, to string) ([]Commit, error)
+}
+
+// MirrorBackend is implemented by Backend implementations that can manage a
+// single shared bare mirror per remote plus cheap linked worktrees derived
+// from it, so installing many packages that pin different commits of the
+// same (or different) remotes doesn't require a full clone per package.
+// Only ExecGitBackend implements it, since it already requires a system git
+// binary; GoGitBackend has no equivalent to `git worktree add --git-dir`,
+// so GitFetcher falls back to a plain clone-or-fetch for it.
+type MirrorBackend interface {
+	Backend
+	// EnsureMirror clones remoteURL as a bare mirror under mirrorRoot the
+	// first time it's seen, or fetches it up to date on subsequent calls,
+	// and returns the mirror's path.
+	EnsureMirror(remoteURL, mirrorRoot string) (string, error)
+	// CheckoutWorktree derives a linked worktree at worktreePath from
+	// mirrorPath, checked out to commitish (or the mirror's default
+	// branch if commitish is empty).
+	CheckoutWorktree(mirrorPath, worktreePath, commitish string) error
+}
+
+// Commit is a single entry in a repository's log.
+type Commit struct {
+	Hash    string
+	Message string
+	When    time.Time
+}
+
+// Kind identifies a Backend implementation that can be selected at runtime.
+type Kind string
+
+const (
+	// KindGoGit is the default, dependency-free backend backed by go-git.
+	KindGoGit Kind = "go-git"
+	// KindExecGit shells out to the system "git" binary. Useful for users
+	// who rely on credential helpers or SSH config go-git doesn't support.
+	KindExecGit Kind = "exec-git"
+)
+
+// New returns the Backend implementation for the given kind.
+func New(kind Kind) (Backend, error) {
+	switch kind {
+	case "", KindGoGit:
+		return &GoGitBackend{}, nil
+	case KindExecGit:
+		return &ExecGitBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unknown vcs backend %q", kind)
+	}
+}